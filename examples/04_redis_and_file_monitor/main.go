@@ -0,0 +1,49 @@
+// Command 04_redis_and_file_monitor demonstrates seeding and monitoring a config struct from a
+// Redis Sentinel deployment using keyspace notifications, alongside a locally mounted file (e.g. a
+// Kubernetes ConfigMap), composing the features added in this chunk: redis.NewWithNotifications,
+// redis.NewSentinelClient/redis.Getter and the file package.
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gtsatsis/harvester"
+	"github.com/gtsatsis/harvester/sync"
+)
+
+type config struct {
+	LogLevel  sync.String `seed:"INFO" flag:"loglevel"`
+	APIToken  sync.String `redis:"harvester/example_04/api_token"`
+	Endpoints sync.String `file:"/etc/example_04/endpoints.json"`
+}
+
+func main() {
+	ctx, cnl := context.WithCancel(context.Background())
+	defer cnl()
+
+	cfg := config{}
+
+	h, err := harvester.New(&cfg).
+		WithRedisSentinelSeed("mymaster", []string{"127.0.0.1:26379"}, "", "", 0).
+		WithRedisSentinelMonitor("mymaster", []string{"127.0.0.1:26379"}, "", "", 0).
+		WithFileMonitor().
+		Create()
+	if err != nil {
+		log.Fatalf("failed to create harvester: %v", err)
+	}
+
+	err = h.Harvest(ctx)
+	if err != nil {
+		log.Fatalf("failed to harvest configuration: %v", err)
+	}
+
+	log.Printf("Config: LogLevel: %s, APIToken: %s, Endpoints: %s\n", cfg.LogLevel.Get(), cfg.APIToken.Get(), cfg.Endpoints.Get())
+
+	// APIToken now updates within roughly one event loop of a `SET harvester/example_04/api_token
+	// ...` against the Sentinel-monitored master, and Endpoints updates whenever
+	// /etc/example_04/endpoints.json is rewritten or swapped in (e.g. a ConfigMap update).
+	time.Sleep(time.Second)
+	log.Printf("Config: LogLevel: %s, APIToken: %s, Endpoints: %s\n", cfg.LogLevel.Get(), cfg.APIToken.Get(), cfg.Endpoints.Get())
+}
@@ -0,0 +1,61 @@
+package consul
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// ErrConflict is returned by Publisher.Publish when the value has been modified concurrently,
+// i.e. the provided version no longer matches the key's current ModifyIndex.
+var ErrConflict = errors.New("consul: concurrent modification detected")
+
+// Publisher writes values back to Consul, using the key's ModifyIndex for optimistic locking so
+// concurrent writers can be detected.
+type Publisher struct {
+	cl    *api.Client
+	dc    string
+	token string
+}
+
+// NewPublisher creates a new publisher.
+func NewPublisher(addr, dc, token string) (*Publisher, error) {
+	if addr == "" {
+		return nil, errors.New("address is empty")
+	}
+	cfg := api.DefaultConfig()
+	cfg.Address = addr
+
+	cl, err := api.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Publisher{cl: cl, dc: dc, token: token}, nil
+}
+
+// Publish writes value to key using a check-and-set operation against version, which must be the
+// ModifyIndex the caller last observed for key (0 for a key that is not expected to exist yet). It
+// returns ErrConflict if the key was modified concurrently.
+//
+// ctx is accepted to satisfy monitor.Publisher alongside redis.Publisher, which does need one for
+// its WATCH/MULTI/EXEC transaction; the consul API client used here has no context-aware CAS call,
+// so ctx is only checked for cancellation before issuing the request.
+func (p *Publisher) Publish(ctx context.Context, key, value string, version uint64) error {
+	if ctx == nil {
+		return errors.New("context is nil")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	pair := &api.KVPair{Key: key, Value: []byte(value), ModifyIndex: version}
+	ok, _, err := p.cl.KV().CAS(pair, &api.WriteOptions{Datacenter: p.dc, Token: p.token})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrConflict
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+// Package file handles the monitor capabilities of harvester using the local filesystem.
+package file
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/gtsatsis/harvester/change"
+	"github.com/gtsatsis/harvester/config"
+	"github.com/gtsatsis/harvester/log"
+)
+
+// Watcher of file changes for `file:`-seeded fields.
+type Watcher struct {
+	paths    []string
+	versions map[string]uint64
+	hashes   map[string]string
+}
+
+// New creates a new watcher for the given file paths.
+func New(paths []string) (*Watcher, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("paths are empty")
+	}
+
+	return &Watcher{
+		paths:    paths,
+		versions: make(map[string]uint64, len(paths)),
+		hashes:   make(map[string]string, len(paths)),
+	}, nil
+}
+
+// Watch the configured paths for changes.
+func (w *Watcher) Watch(ctx context.Context, ch chan<- []*change.Change) error {
+	if ctx == nil {
+		return errors.New("context is nil")
+	}
+	if ch == nil {
+		return errors.New("change channel is nil")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	// Watch the parent directory of each file rather than the file itself, so renames performed
+	// by editors (write-then-rename), Kubernetes ConfigMap symlink swaps and Docker secret updates
+	// are all observed; watching the file's inode directly would miss these since the inode the
+	// watch was set up on may no longer be the one the path resolves to.
+	dirs := make(map[string]bool)
+	for _, path := range w.paths {
+		dirs[filepath.Dir(path)] = true
+	}
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			_ = watcher.Close()
+			return err
+		}
+	}
+
+	go w.monitor(ctx, watcher, ch)
+	return nil
+}
+
+func (w *Watcher) monitor(ctx context.Context, watcher *fsnotify.Watcher, ch chan<- []*change.Change) {
+	defer func() {
+		_ = watcher.Close()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Errorf("file watcher error: %v", err)
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			w.handleEvent(event, ch)
+		}
+	}
+}
+
+// handleEvent re-checks every watched path whenever a write, create, rename or remove happens in
+// one of their parent directories (chmod-only events are ignored), rather than only reacting when the event's own name matches a watched path. A
+// Kubernetes ConfigMap update never touches the watched path's own name: kubelet writes a new
+// `..<timestamp>` directory and atomically renames the `..data` symlink to point at it, so the
+// event fsnotify reports is for `..data`/`..<timestamp>`, not for e.g. `/etc/config/app.yaml`
+// itself (which is a symlink through `..data/app.yaml` to the real file). Re-resolving and
+// re-reading every watched path on each such directory event, instead of filtering by event.Name, is
+// what actually survives that indirection (matching how spf13/viper's WatchConfig copes with the
+// same symlink swap).
+func (w *Watcher) handleEvent(event fsnotify.Event, ch chan<- []*change.Change) {
+	if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) == 0 {
+		return
+	}
+
+	var changes []*change.Change
+	for _, path := range w.paths {
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			log.Errorf("failed to resolve symlinks for %s after %s event: %v", path, event.Op, err)
+			continue
+		}
+
+		body, err := ioutil.ReadFile(resolved)
+		if err != nil {
+			log.Errorf("failed to read file %s after %s event: %v", resolved, event.Op, err)
+			continue
+		}
+
+		hash := w.hash(body)
+		if hash == w.hashes[path] {
+			continue
+		}
+		w.hashes[path] = hash
+		w.versions[path]++
+
+		changes = append(changes, change.New(config.SourceFile, path, string(body), w.versions[path]))
+	}
+
+	if len(changes) == 0 {
+		return
+	}
+	ch <- changes
+}
+
+func (w *Watcher) hash(value []byte) string {
+	hash := md5.Sum(value)
+	return hex.EncodeToString(hash[:])
+}
@@ -16,6 +16,13 @@ type Watcher interface {
 	Watch(ctx context.Context, ch chan<- []*change.Change) error
 }
 
+// Publisher writes a value back through the source it was read from, using the version the
+// caller last observed for optimistic-locking conflict detection. Implementations (consul.Publisher,
+// redis.Publisher) return ErrConflict, defined in their own package, when version is stale.
+type Publisher interface {
+	Publish(ctx context.Context, key, value string, version uint64) error
+}
+
 type sourceMap map[config.Source]map[string]*config.Field
 
 // Monitor for configuration changes.
@@ -23,6 +30,11 @@ type Monitor struct {
 	cfg *config.Config
 	mp  sourceMap
 	ww  []Watcher
+
+	pp        map[config.Source]Publisher
+	versions  map[*config.Field]uint64
+	depValues map[config.Source]map[string]string
+	templates []*templateField
 }
 
 // New constructor.
@@ -37,7 +49,23 @@ func New(cfg *config.Config, ww ...Watcher) (*Monitor, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Monitor{cfg: cfg, mp: mp, ww: ww}, nil
+	return &Monitor{
+		cfg:       cfg,
+		mp:        mp,
+		ww:        ww,
+		pp:        make(map[config.Source]Publisher),
+		versions:  make(map[*config.Field]uint64),
+		depValues: make(map[config.Source]map[string]string),
+	}, nil
+}
+
+func (m *Monitor) cacheDepValue(c *change.Change) {
+	vv, ok := m.depValues[c.Source()]
+	if !ok {
+		vv = make(map[string]string)
+		m.depValues[c.Source()] = vv
+	}
+	vv[c.Key()] = c.Value()
 }
 
 func generateMap(ff []*config.Field) (sourceMap, error) {
@@ -89,22 +117,65 @@ func (m *Monitor) monitor(ctx context.Context, ch <-chan []*change.Change) {
 
 func (m *Monitor) applyChange(cc []*change.Change) {
 	for _, c := range cc {
+		m.cacheDepValue(c)
+
 		mp, ok := m.mp[c.Source()]
-		if !ok {
+		if ok {
+			fld, ok := mp[c.Key()]
+			if !ok {
+				log.Debugf("key %s not found", c.Key())
+			} else {
+				err := fld.Set(c.Value(), c.Version())
+				if err != nil {
+					log.Errorf("failed to set value %s of type %s on field %s from source %s: %v",
+						c.Value(), fld.Type(), fld.Name(), c.Source(), err)
+				} else {
+					m.versions[fld] = c.Version()
+				}
+			}
+		} else {
 			log.Debugf("source %s not found", c.Source())
-			continue
 		}
-		fld, ok := mp[c.Key()]
+
+		m.renderDependentTemplates(c.Source(), c.Key())
+	}
+}
+
+// RegisterPublisher attaches a Publisher that Publish can dispatch writes to for fields whose
+// canonical source is src.
+func (m *Monitor) RegisterPublisher(src config.Source, p Publisher) {
+	m.pp[src] = p
+}
+
+// Publish writes value back through a source in f.Sources() that has a registered Publisher
+// (SourceSeed, SourceEnv, SourceFlag and SourceFile have no Publisher implementation). f.Sources()
+// is a map, so when more than one of f's sources has a registered Publisher, which one is used is
+// not defined. It uses the version last observed
+// for f by the Monitor's own watchers (or by a prior successful Publish) for conflict detection,
+// and returns ErrConflict, as defined by the Publisher's own package, if it is stale.
+func (m *Monitor) Publish(ctx context.Context, f *config.Field, value string) error {
+	if f == nil {
+		return errors.New("field is nil")
+	}
+
+	for source, key := range f.Sources() {
+		p, ok := m.pp[source]
 		if !ok {
-			log.Debugf("key %s not found", c.Key())
 			continue
 		}
 
-		err := fld.Set(c.Value(), c.Version())
-		if err != nil {
-			log.Errorf("failed to set value %s of type %s on field %s from source %s: %v",
-				c.Value(), fld.Type(), fld.Name(), c.Source(), err)
-			continue
+		version := m.versions[f]
+		if err := p.Publish(ctx, key, value, version); err != nil {
+			return err
 		}
+
+		newVersion := version + 1
+		if err := f.Set(value, newVersion); err != nil {
+			return err
+		}
+		m.versions[f] = newVersion
+		return nil
 	}
+
+	return fmt.Errorf("field %s has no source with a registered publisher", f.Name())
 }
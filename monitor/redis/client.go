@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"errors"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// SentinelOptions describes a Sentinel-monitored Redis deployment. Password authenticates against
+// the Redis master/replicas, while SentinelPassword authenticates against the Sentinel processes
+// themselves, since the two are commonly configured with different credentials (or none at all for
+// Sentinel).
+type SentinelOptions struct {
+	MasterName       string
+	SentinelAddrs    []string
+	Password         string
+	SentinelPassword string
+	DB               int
+}
+
+// NewSentinelClient builds a redis.UniversalClient backed by a Sentinel-monitored master/replica
+// set, suitable for passing to New, NewWithNotifications or a seed.Getter.
+func NewSentinelClient(opts SentinelOptions) (redis.UniversalClient, error) {
+	if opts.MasterName == "" {
+		return nil, errors.New("master name is empty")
+	}
+	if len(opts.SentinelAddrs) == 0 {
+		return nil, errors.New("sentinel addresses are empty")
+	}
+
+	return redis.NewFailoverClient(&redis.FailoverOptions{
+		MasterName:       opts.MasterName,
+		SentinelAddrs:    opts.SentinelAddrs,
+		Password:         opts.Password,
+		SentinelPassword: opts.SentinelPassword,
+		DB:               opts.DB,
+	}), nil
+}
+
+// ClusterOptions describes a Redis Cluster deployment.
+type ClusterOptions struct {
+	Addrs    []string
+	Password string
+}
+
+// NewClusterClient builds a redis.UniversalClient backed by a Redis Cluster, suitable for passing
+// to New, NewWithNotifications or a seed.Getter.
+//
+// Note for NewWithNotifications: CONFIG GET/SET notify-keyspace-events are keyless commands, so
+// through a cluster client they are routed to a single arbitrary node rather than broadcast,
+// while keyspace notifications are generated per-node on whichever master executes a write.
+// ensureNotifyKeyspaceEvents detects a cluster client and checks/fixes every master individually
+// via ForEachMaster rather than relying on a single CONFIG SET reaching the whole cluster.
+func NewClusterClient(opts ClusterOptions) (redis.UniversalClient, error) {
+	if len(opts.Addrs) == 0 {
+		return nil, errors.New("addrs are empty")
+	}
+
+	return redis.NewClusterClient(&redis.ClusterOptions{
+		Addrs:    opts.Addrs,
+		Password: opts.Password,
+	}), nil
+}
@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Getter implements seed.Getter backed by a redis.UniversalClient, so the same client passed to
+// New or NewWithNotifications (e.g. one built via NewSentinelClient or NewClusterClient) can seed a field from Redis (Sentinel/Cluster
+// included) as well as watch it afterward.
+type Getter struct {
+	ctx    context.Context
+	client redis.UniversalClient
+}
+
+// NewGetter creates a new Getter. ctx is retained for the lifetime of the Getter since
+// seed.Getter's Get method takes no context of its own.
+func NewGetter(ctx context.Context, client redis.UniversalClient) (*Getter, error) {
+	if ctx == nil {
+		return nil, errors.New("context is nil")
+	}
+	if client == nil {
+		return nil, errors.New("client is nil")
+	}
+	return &Getter{ctx: ctx, client: client}, nil
+}
+
+// Get fetches key's current value and version. A nil value with no error means key does not
+// exist, matching seed.Seeder's expectations for an optional source. The version is read from
+// key's companion "{key}.version" counter (the same one Publisher advances on every write); a key
+// that has never been published through Publisher seeds at version 0.
+func (g *Getter) Get(key string) (*string, uint64, error) {
+	value, err := g.client.Get(g.ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	version, err := g.currentVersion(key)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &value, version, nil
+}
+
+func (g *Getter) currentVersion(key string) (uint64, error) {
+	versionKey := "{" + key + "}" + versionKeySuffix
+	raw, err := g.client.Get(g.ctx, versionKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(raw, 10, 64)
+}
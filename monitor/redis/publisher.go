@@ -0,0 +1,130 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/gtsatsis/harvester/log"
+)
+
+// releaseReservationScript deletes a reservation key only if it still holds the token the caller
+// set it with, so a stale release (e.g. after the reservation's TTL already expired and a
+// different writer acquired it) can't delete someone else's active lease.
+var releaseReservationScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+else
+	return 0
+end
+`)
+
+// ErrConflict is returned by Publisher.Publish when another writer holds the key's reservation, or
+// when the key's version no longer matches what the caller last observed.
+var ErrConflict = errors.New("redis: concurrent modification detected")
+
+// reservationTTL bounds how long a Publisher holds a write lease on a key, so a writer that
+// crashes mid-publish doesn't lock the key out forever.
+const reservationTTL = 5 * time.Second
+
+// versionKeySuffix is appended to a key to store its version counter alongside its value, since
+// plain Redis values have no equivalent to Consul's ModifyIndex.
+const versionKeySuffix = ".version"
+
+// Publisher writes values back to Redis. Since plain Redis has no built-in equivalent to Consul's
+// ModifyIndex, writes coordinate through a companion "{<key>}.reservation" lease acquired with
+// `SET NX` with a reservationTTL expiry (following the reservation pattern used for voltha-style kvstore writers), and the
+// version itself is tracked in a companion "{<key>}.version" counter that is checked and advanced
+// inside a WATCH/MULTI/EXEC transaction, so a concurrent writer that slips in between the
+// reservation and the write is still detected. The companion keys are hash-tagged on key itself
+// (`{key}.version` rather than `key.version`) so that against a *redis.ClusterClient they hash to
+// the same slot as key: WATCH/MULTI/EXEC and EVAL all require every key they touch to live on one
+// node, and a bare, un-tagged companion key would routinely land on a different slot than key and
+// fail with CROSSSLOT.
+type Publisher struct {
+	client redis.UniversalClient
+}
+
+// NewPublisher creates a new publisher.
+func NewPublisher(client redis.UniversalClient) (*Publisher, error) {
+	if client == nil {
+		return nil, errors.New("client is nil")
+	}
+	return &Publisher{client: client}, nil
+}
+
+// Publish writes value to key, provided version matches the key's current version counter (0 for a
+// key that is not expected to exist yet). It returns ErrConflict if the key's reservation is
+// already held by another writer, or if the key's version has moved on since the caller last read
+// it.
+func (p *Publisher) Publish(ctx context.Context, key, value string, version uint64) error {
+	reservationKey := fmt.Sprintf("{%s}.reservation", key)
+	versionKey := fmt.Sprintf("{%s}%s", key, versionKeySuffix)
+
+	token, err := reservationToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate reservation token for key %s: %w", key, err)
+	}
+
+	acquired, err := p.client.SetNX(ctx, reservationKey, token, reservationTTL).Result()
+	if err != nil {
+		return fmt.Errorf("failed to acquire reservation for key %s: %w", key, err)
+	}
+	if !acquired {
+		return ErrConflict
+	}
+	defer func() {
+		if err := releaseReservationScript.Run(ctx, p.client, []string{reservationKey}, token).Err(); err != nil {
+			log.Errorf("failed to release reservation for key %s: %v", key, err)
+		}
+	}()
+
+	txf := func(tx *redis.Tx) error {
+		current, err := p.currentVersion(ctx, tx, versionKey)
+		if err != nil {
+			return err
+		}
+		if current != version {
+			return ErrConflict
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, value, 0)
+			pipe.Set(ctx, versionKey, version+1, 0)
+			return nil
+		})
+		return err
+	}
+
+	err = p.client.Watch(ctx, txf, key, versionKey)
+	if errors.Is(err, redis.TxFailedErr) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (p *Publisher) currentVersion(ctx context.Context, tx *redis.Tx, versionKey string) (uint64, error) {
+	raw, err := tx.Get(ctx, versionKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(raw, 10, 64)
+}
+
+// reservationToken generates a random value to identify the holder of a reservation, so its
+// release can be guarded against deleting a different writer's reservation.
+func reservationToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
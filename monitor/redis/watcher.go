@@ -6,6 +6,8 @@ import (
 	"crypto/md5"
 	"encoding/hex"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -14,6 +16,15 @@ import (
 	"github.com/gtsatsis/harvester/log"
 )
 
+// notifyKeyspaceEventsFlags is the value the notification-based Watcher sets notify-keyspace-events
+// to when it is allowed to fix it: keyspace ("K") and keyevent ("E") notifications for all event
+// classes ("A"). This is more than notifyKeyspaceEventsSufficient requires.
+const notifyKeyspaceEventsFlags = "KEA"
+
+// reconnectBackoff is how long the notification Watcher waits before re-subscribing after its
+// pub/sub connection drops.
+const reconnectBackoff = time.Second
+
 // Watcher of Redis changes.
 type Watcher struct {
 	client       redis.UniversalClient
@@ -21,9 +32,14 @@ type Watcher struct {
 	versions     []uint64
 	hashes       []string
 	pollInterval time.Duration
+
+	db                int
+	useNotifications  bool
+	fixNotifyKeyspace bool
 }
 
-// New watcher.
+// New watcher. It polls the given keys with MGET every pollInterval and emits a change for every
+// key whose value hash changed since the last poll.
 func New(client redis.UniversalClient, pollInterval time.Duration, keys []string) (*Watcher, error) {
 	if client == nil {
 		return nil, errors.New("client is nil")
@@ -44,6 +60,37 @@ func New(client redis.UniversalClient, pollInterval time.Duration, keys []string
 	}, nil
 }
 
+// NewWithNotifications creates a watcher that subscribes to Redis keyspace notifications instead
+// of polling. db is the logical Redis database the keys live in (used to build the
+// `__keyspace@<db>__:<key>` channel names); it should match whatever database the client is
+// configured against. When fixNotifyKeyspace is true, the watcher will CONFIG SET
+// notify-keyspace-events on the server if it does not already enable keyspace notifications for
+// the set/del/expire event classes (see notifyKeyspaceEventsSufficient); otherwise it only logs a warning and keeps running with whatever is configured, since
+// pub/sub events will simply never arrive for the missing classes.
+//
+// Because pub/sub delivery is not guaranteed across a disconnect, the watcher falls back to a full
+// MGET reconciliation pass (reusing the same hash/version bookkeeping as the polling Watcher) every
+// time the subscription is (re-)established, so any notification missed while disconnected is still
+// flushed as a change.
+func NewWithNotifications(client redis.UniversalClient, db int, fixNotifyKeyspace bool, keys []string) (*Watcher, error) {
+	if client == nil {
+		return nil, errors.New("client is nil")
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("keys are empty")
+	}
+
+	return &Watcher{
+		client:            client,
+		keys:              keys,
+		versions:          make([]uint64, len(keys)),
+		hashes:            make([]string, len(keys)),
+		db:                db,
+		useNotifications:  true,
+		fixNotifyKeyspace: fixNotifyKeyspace,
+	}, nil
+}
+
 // Watch keys and changes.
 func (w *Watcher) Watch(ctx context.Context, ch chan<- []*change.Change) error {
 	if ctx == nil {
@@ -53,6 +100,19 @@ func (w *Watcher) Watch(ctx context.Context, ch chan<- []*change.Change) error {
 		return errors.New("change channel is nil")
 	}
 
+	if w.useNotifications {
+		// Do one check/fix synchronously so a failing CONFIG GET/SET is reported as an error from
+		// Watch itself (an insufficient setting that may not be fixed is only logged);
+		// monitorNotifications re-checks on every (re)subscribe after
+		// that, since the setting can be reset later (server restart, config reload, failover to a
+		// differently-configured replica).
+		if err := w.ensureNotifyKeyspaceEvents(ctx); err != nil {
+			return err
+		}
+		go w.monitorNotifications(ctx, ch)
+		return nil
+	}
+
 	go w.monitor(ctx, ch)
 	return nil
 }
@@ -106,3 +166,179 @@ func (w *Watcher) hash(value string) string {
 	hash := md5.Sum([]byte(value))
 	return hex.EncodeToString(hash[:])
 }
+
+// ensureNotifyKeyspaceEvents checks that the server has notify-keyspace-events configured with at
+// least keyspace notifications for set/del/expire ("K" together with "A", or with "g", "$" and "x";
+// see notifyKeyspaceEventsSufficient). If not, it either fixes it via CONFIG SET or logs a warning,
+// depending on fixNotifyKeyspace.
+//
+// CONFIG GET/SET are keyless commands: against a single node or a Sentinel-backed FailoverClient
+// there is only one master to check, but against a ClusterClient they would otherwise be routed to
+// one arbitrary node while notifications are generated per-node on whichever master executes a
+// write. For a *redis.ClusterClient this checks (and, if allowed, fixes) every master individually
+// via ForEachMaster instead.
+func (w *Watcher) ensureNotifyKeyspaceEvents(ctx context.Context) error {
+	if cluster, ok := w.client.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, master *redis.Client) error {
+			return w.ensureNotifyKeyspaceEventsOn(ctx, master)
+		})
+	}
+	return w.ensureNotifyKeyspaceEventsOn(ctx, w.client)
+}
+
+// ensureNotifyKeyspaceEventsOn runs the CONFIG GET/SET check against a single node.
+func (w *Watcher) ensureNotifyKeyspaceEventsOn(ctx context.Context, node redis.UniversalClient) error {
+	res, err := node.ConfigGet(ctx, "notify-keyspace-events").Result()
+	if err != nil {
+		return fmt.Errorf("failed to CONFIG GET notify-keyspace-events: %w", err)
+	}
+
+	var current string
+	for i := 0; i+1 < len(res); i += 2 {
+		if res[i] == "notify-keyspace-events" {
+			current, _ = res[i+1].(string)
+		}
+	}
+
+	if notifyKeyspaceEventsSufficient(current) {
+		return nil
+	}
+
+	if !w.fixNotifyKeyspace {
+		log.Errorf("notify-keyspace-events is set to %q, which is missing flags required for keyspace notifications "+
+			"(need at least %q); if this is a Redis Cluster, every master must be fixed individually, since "+
+			"CONFIG SET on a cluster client only reaches one node - set it on the Redis server(s) or pass "+
+			"fixNotifyKeyspace=true", current, notifyKeyspaceEventsFlags)
+		return nil
+	}
+
+	if err := node.ConfigSet(ctx, "notify-keyspace-events", notifyKeyspaceEventsFlags).Err(); err != nil {
+		return fmt.Errorf("failed to CONFIG SET notify-keyspace-events: %w", err)
+	}
+	log.Debugf("notify-keyspace-events was %q, set it to %q", current, notifyKeyspaceEventsFlags)
+	return nil
+}
+
+// notifyKeyspaceEventsSufficient reports whether current already enables keyspace notifications
+// ("K") together with enough event classes to observe set/del/expire: "A" (alias for all classes),
+// or "g" (generic commands, e.g. DEL/EXPIRE), "$" (string commands, e.g. SET) and "x" (keys
+// actually expiring, as opposed to EXPIRE merely being issued) together.
+func notifyKeyspaceEventsSufficient(current string) bool {
+	if !strings.ContainsRune(current, 'K') {
+		return false
+	}
+	if strings.ContainsRune(current, 'A') {
+		return true
+	}
+	return strings.ContainsRune(current, 'g') && strings.ContainsRune(current, '$') && strings.ContainsRune(current, 'x')
+}
+
+// monitorNotifications subscribes to keyspace notifications for w.keys and, on any notification
+// for a key, GETs it and emits a change if its value hash differs from the last one seen; a key
+// that no longer exists (deleted or expired) emits no change. Channel names are the exact, fully-known
+// `__keyspace@<db>__:<key>` strings built from w.keys, so this uses Subscribe (exact match)
+// rather than PSubscribe (glob-pattern match), since a key containing a Redis glob metacharacter
+// (*, ?, [, ]) would otherwise be matched incorrectly. The subscription is wrapped in a reconnect
+// loop: if the pub/sub connection drops, it re-subscribes (re-checking notify-keyspace-events
+// first) and runs a full MGET reconciliation pass, so any notification missed while disconnected
+// still results in a change being emitted.
+func (w *Watcher) monitorNotifications(ctx context.Context, ch chan<- []*change.Change) {
+	keyIndex := make(map[string]int, len(w.keys))
+	channels := make([]string, len(w.keys))
+	for i, key := range w.keys {
+		keyIndex[key] = i
+		channels[i] = fmt.Sprintf("__keyspace@%d__:%s", w.db, key)
+	}
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// notify-keyspace-events is server-side state that can be reset independently of this
+		// watcher (server restart without a persisted redis.conf, config reload, failover to a
+		// differently-configured replica), so re-check/fix it on every (re)subscribe rather than
+		// only once in Watch, or a reset would silently stop notifications forever.
+		if err := w.ensureNotifyKeyspaceEvents(ctx); err != nil {
+			log.Errorf("failed to ensure notify-keyspace-events before subscribing: %v", err)
+			if !w.sleep(ctx, reconnectBackoff) {
+				return
+			}
+			continue
+		}
+
+		pubsub := w.client.Subscribe(ctx, channels...)
+		if _, err := pubsub.Receive(ctx); err != nil {
+			log.Errorf("failed to subscribe to keyspace notifications: %v", err)
+			_ = pubsub.Close()
+			if !w.sleep(ctx, reconnectBackoff) {
+				return
+			}
+			continue
+		}
+
+		// A (re)subscribe may have missed updates, so always reconcile from scratch first.
+		w.getValues(ctx, ch)
+
+		msgCh := pubsub.Channel()
+	receive:
+		for {
+			select {
+			case <-ctx.Done():
+				_ = pubsub.Close()
+				return
+			case msg, ok := <-msgCh:
+				if !ok {
+					break receive
+				}
+				w.handleNotification(ctx, keyIndex, msg, ch)
+			}
+		}
+
+		_ = pubsub.Close()
+		log.Errorf("keyspace notification subscription closed, reconnecting")
+		if !w.sleep(ctx, reconnectBackoff) {
+			return
+		}
+	}
+}
+
+func (w *Watcher) handleNotification(ctx context.Context, keyIndex map[string]int, msg *redis.Message, ch chan<- []*change.Change) {
+	_, key, ok := strings.Cut(msg.Channel, "__:")
+	if !ok {
+		return
+	}
+	i, ok := keyIndex[key]
+	if !ok {
+		return
+	}
+
+	value, err := w.client.Get(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return
+		}
+		log.Errorf("failed to GET key %s after %s event: %v", key, msg.Payload, err)
+		return
+	}
+
+	hash := w.hash(value)
+	if hash == w.hashes[i] {
+		return
+	}
+	w.versions[i]++
+	w.hashes[i] = hash
+
+	ch <- []*change.Change{change.New(config.SourceRedis, key, value, w.versions[i])}
+}
+
+func (w *Watcher) sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,158 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/gtsatsis/harvester/config"
+	"github.com/gtsatsis/harvester/log"
+)
+
+// templateDep identifies a single Consul/Redis key a template field depends on.
+type templateDep struct {
+	source config.Source
+	key    string
+}
+
+// templateField is a config.Field whose value is computed from a Go text/template referencing one
+// or more other watched keys, e.g. `{{consul "db/user"}}:{{redis "db/pass"}}@{{consul "db/host"}}`.
+type templateField struct {
+	fld      *config.Field
+	tpl      *template.Template
+	deps     []templateDep
+	version  uint64
+	rendered string
+}
+
+// RegisterTemplate turns fld into a computed field driven by tplText, a Go text/template that may
+// call `consul "key"` and/or `redis "key"` to reference other watched keys. The template's
+// dependency keys are discovered by a dry run of the template against recording stand-ins for
+// those funcs, then cached for renderDependentTemplates to match incoming changes against; fld
+// itself is left untouched until every dependency key has a value.
+//
+// The caller is still responsible for making sure every dependency key discovered here is actually
+// passed to a running Consul/Redis Watcher (e.g. via Dependencies), since Monitor only reacts to
+// changes it is handed - it does not start watches on its own.
+func (m *Monitor) RegisterTemplate(tplText string, fld *config.Field) (*templateField, error) {
+	if fld == nil {
+		return nil, fmt.Errorf("field is nil")
+	}
+
+	tpl, err := template.New(fld.Name()).Funcs(template.FuncMap{
+		"consul": m.depLookup(config.SourceConsul),
+		"redis":  m.depLookup(config.SourceRedis),
+	}).Parse(tplText)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template for field %s: %w", fld.Name(), err)
+	}
+
+	deps := discoverTemplateDeps(tplText)
+	if len(deps) == 0 {
+		return nil, fmt.Errorf("template for field %s has no consul/redis dependencies", fld.Name())
+	}
+
+	tf := &templateField{fld: fld, tpl: tpl, deps: deps}
+	m.templates = append(m.templates, tf)
+	return tf, nil
+}
+
+// Dependencies returns the keys registered templates depend on for the given source, so the
+// caller can pass them to the Watcher it constructs for that source alongside any directly bound
+// fields.
+func (m *Monitor) Dependencies(source config.Source) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, tf := range m.templates {
+		for _, d := range tf.deps {
+			if d.source != source || seen[d.key] {
+				continue
+			}
+			seen[d.key] = true
+			keys = append(keys, d.key)
+		}
+	}
+	return keys
+}
+
+// renderDependentTemplates re-renders every registered template that depends on (source, key),
+// applying the result to its field if it differs from the last cached render.
+func (m *Monitor) renderDependentTemplates(source config.Source, key string) {
+	for _, tf := range m.templates {
+		if !tf.dependsOn(source, key) {
+			continue
+		}
+
+		rendered, err := m.render(tf)
+		if err != nil {
+			log.Errorf("failed to render template for field %s: %v", tf.fld.Name(), err)
+			continue
+		}
+		if rendered == tf.rendered {
+			continue
+		}
+
+		tf.version++
+		if err := tf.fld.Set(rendered, tf.version); err != nil {
+			log.Errorf("failed to set rendered template value on field %s: %v", tf.fld.Name(), err)
+			continue
+		}
+		tf.rendered = rendered
+	}
+}
+
+func (m *Monitor) render(tf *templateField) (string, error) {
+	sb := strings.Builder{}
+	if err := tf.tpl.Execute(&sb, nil); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func (m *Monitor) depLookup(source config.Source) func(string) (string, error) {
+	return func(key string) (string, error) {
+		v, ok := m.depValues[source][key]
+		if !ok {
+			return "", fmt.Errorf("%s key %s has not been resolved yet", source, key)
+		}
+		return v, nil
+	}
+}
+
+func (tf *templateField) dependsOn(source config.Source, key string) bool {
+	for _, d := range tf.deps {
+		if d.source == source && d.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// discoverTemplateDeps scans tplText for `consul "key"`/`redis "key"` calls by executing it once
+// against recording stand-ins for those funcs, rather than regexing the template source, so
+// anything text/template itself considers a call (including keys computed from `{{with}}`/`{{if}}`
+// branches that always take the same path) is still found.
+func discoverTemplateDeps(tplText string) []templateDep {
+	var deps []templateDep
+	seen := make(map[templateDep]bool)
+	record := func(source config.Source) func(string) (string, error) {
+		return func(key string) (string, error) {
+			d := templateDep{source: source, key: key}
+			if !seen[d] {
+				seen[d] = true
+				deps = append(deps, d)
+			}
+			return "", nil
+		}
+	}
+
+	tpl, err := template.New("discover").Funcs(template.FuncMap{
+		"consul": record(config.SourceConsul),
+		"redis":  record(config.SourceRedis),
+	}).Parse(tplText)
+	if err != nil {
+		return nil
+	}
+	_ = tpl.Execute(&strings.Builder{}, nil)
+	return deps
+}